@@ -0,0 +1,164 @@
+package mongodialect
+
+import (
+    "context"
+    "github.com/daspoet/mongodialect/interfaces"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// BulkOps accumulates write operations to be flushed together as a
+// single BulkWrite call by Repository.Bulk. Operations run in the
+// order they are queued.
+//
+// BulkOps runs the same id population and lifecycle hooks as the
+// corresponding single-document Repository methods: InsertOne and
+// ReplaceOne populate a zero id field and run BeforeInsert/AfterInsert,
+// UpdateOne runs BeforeUpdate/AfterUpdate, and DeleteOne runs
+// BeforeDelete/AfterDelete. Before-hooks run as the operation is
+// queued; after-hooks run once BulkWrite has flushed successfully.
+type BulkOps struct {
+    r      *Repository
+    ctx    context.Context
+    models []mongo.WriteModel
+    after  []func() error
+}
+
+// InsertOne queues the insertion of v.
+//
+// It fails if v cannot be decoded into r's base type, or if a BeforeInsert hook fails.
+func (b *BulkOps) InsertOne(v interface{}) error {
+    dec, err := decodeIntoBase(b.r, v)
+    if err != nil {
+        return err
+    }
+    populateID(b.r, dec)
+
+    if err := beforeInsert(b.r, b.ctx, dec); err != nil {
+        return err
+    }
+
+    b.models = append(b.models, mongo.NewInsertOneModel().SetDocument(dec))
+    b.after = append(b.after, func() error {
+        return afterInsert(b.r, b.ctx, dec)
+    })
+    return nil
+}
+
+// UpdateOne queues an update of at most one document
+// matching f, setting changes via "$set".
+//
+// If r's base type declares a protection field, the queued update is
+// protected the same way Repository.Update's is: it reads the
+// document's current protection value, additionally filters on it,
+// and atomically increments it. Unlike Update, UpdateOne cannot
+// surface ErrStaleDocument for a stale match, since BulkWrite reports
+// only aggregate match counts for the whole batch, not per operation;
+// a stale protected update queued through Bulk simply matches
+// nothing, the same as if f itself had matched nothing.
+//
+// It fails if a BeforeUpdate hook fails, or if reading the document's
+// current protection value fails.
+func (b *BulkOps) UpdateOne(f interfaces.Filter, changes map[string]interface{}) error {
+    filterMap(b.r, changes)
+
+    if err := beforeUpdate(b.r, b.ctx, f, changes); err != nil {
+        return err
+    }
+
+    protected := b.r.protectionField != ""
+    if protected {
+        delete(changes, b.r.protectionField)
+    }
+
+    if len(changes) == 0 {
+        return nil
+    }
+
+    updates := bson.D{{"$set", changes}}
+    filter := f
+
+    if protected {
+        current, found, err := currentProtectionValue(b.r, b.ctx, f)
+        if err != nil {
+            return err
+        }
+        if !found {
+            return nil
+        }
+
+        filter = withFilterValue(f, b.r.protectionField, current)
+        updates = append(updates, bson.E{Key: "$inc", Value: bson.M{b.r.protectionField: 1}})
+    }
+
+    b.models = append(b.models, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(updates))
+    b.after = append(b.after, func() error {
+        return afterUpdate(b.r, b.ctx, f, changes)
+    })
+    return nil
+}
+
+// DeleteOne queues the deletion of at most one document matching f.
+//
+// It fails if a BeforeDelete hook fails.
+func (b *BulkOps) DeleteOne(f interfaces.Filter) error {
+    if err := beforeDelete(b.r, b.ctx, f); err != nil {
+        return err
+    }
+
+    b.models = append(b.models, mongo.NewDeleteOneModel().SetFilter(f))
+    b.after = append(b.after, func() error {
+        return afterDelete(b.r, b.ctx, f)
+    })
+    return nil
+}
+
+// ReplaceOne queues the replacement of at most one document matching f with v.
+//
+// It fails if v cannot be decoded into r's base type, or if a BeforeInsert hook fails.
+func (b *BulkOps) ReplaceOne(f interfaces.Filter, v interface{}) error {
+    dec, err := decodeIntoBase(b.r, v)
+    if err != nil {
+        return err
+    }
+    populateID(b.r, dec)
+
+    if err := beforeInsert(b.r, b.ctx, dec); err != nil {
+        return err
+    }
+
+    b.models = append(b.models, mongo.NewReplaceOneModel().SetFilter(f).SetReplacement(dec))
+    b.after = append(b.after, func() error {
+        return afterInsert(b.r, b.ctx, dec)
+    })
+    return nil
+}
+
+// Bulk passes a *BulkOps to fn for it to queue write operations on,
+// and once fn returns, flushes all queued operations to r's
+// collection with a single BulkWrite call, before running the
+// after-hooks queued alongside those operations.
+//
+// It fails if fn returns an error, or if there is an internal MongoDB error.
+func (r *Repository) Bulk(ctx context.Context, fn func(ops *BulkOps) error) (*mongo.BulkWriteResult, error) {
+    ops := &BulkOps{r: r, ctx: ctx}
+    if err := fn(ops); err != nil {
+        return nil, err
+    }
+
+    if len(ops.models) == 0 {
+        return &mongo.BulkWriteResult{}, nil
+    }
+
+    result, err := collection(r).BulkWrite(ctx, ops.models)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, after := range ops.after {
+        if err := after(); err != nil {
+            return result, err
+        }
+    }
+    return result, nil
+}
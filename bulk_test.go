@@ -0,0 +1,179 @@
+package mongodialect
+
+import (
+    "context"
+    "github.com/daspoet/mongodialect/interfaces"
+    "go.mongodb.org/mongo-driver/mongo"
+    "reflect"
+    "testing"
+)
+
+type bulkTestDocument struct {
+    ID   string `bson:"_id"`
+    Name string `bson:"name"`
+}
+
+func newBulkTestRepository(t *testing.T) *Repository {
+    t.Helper()
+
+    driver := NewDriver(NewDatabaseURL("localhost", 27017), "testBase")
+    r, err := NewRepository(reflect.TypeOf(new(bulkTestDocument)), driver, "bulk", interfaces.StringIDProvider{Field: "_id"})
+    if err != nil {
+        t.Fatalf("NewRepository() failed: %v", err)
+    }
+    return r
+}
+
+func TestBulkOps_InsertOne_PopulatesIDAndRunsHooksAtTheRightTime(t *testing.T) {
+    r := newBulkTestRepository(t)
+
+    var beforeRan, afterRan bool
+    r.Use(Hooks{
+        BeforeInsert: func(ctx context.Context, v interface{}) error {
+            beforeRan = true
+            return nil
+        },
+        AfterInsert: func(ctx context.Context, v interface{}) error {
+            afterRan = true
+            return nil
+        },
+    })
+
+    ops := &BulkOps{r: r, ctx: context.Background()}
+    if err := ops.InsertOne(&bulkTestDocument{Name: "Kenobi"}); err != nil {
+        t.Fatalf("InsertOne() failed: %v", err)
+    }
+
+    if !beforeRan {
+        t.Error("BeforeInsert did not run as InsertOne was queued")
+    }
+    if afterRan {
+        t.Error("AfterInsert ran before BulkWrite flushed anything")
+    }
+    if len(ops.models) != 1 || len(ops.after) != 1 {
+        t.Fatalf("len(ops.models)=%d len(ops.after)=%d, want 1 and 1", len(ops.models), len(ops.after))
+    }
+
+    model, ok := ops.models[0].(*mongo.InsertOneModel)
+    if !ok {
+        t.Fatalf("ops.models[0] = %T, want *mongo.InsertOneModel", ops.models[0])
+    }
+    if doc, ok := model.Document.(*bulkTestDocument); !ok || doc.ID == "" {
+        t.Errorf("queued document = %+v, want a *bulkTestDocument with a populated ID", model.Document)
+    }
+
+    if err := ops.after[0](); err != nil {
+        t.Fatalf("after closure failed: %v", err)
+    }
+    if !afterRan {
+        t.Error("AfterInsert did not run once the after closure was invoked")
+    }
+}
+
+func TestBulkOps_UpdateOne_RunsBeforeUpdateAtQueueTime(t *testing.T) {
+    r := newBulkTestRepository(t)
+
+    var beforeRan, afterRan bool
+    r.Use(Hooks{
+        BeforeUpdate: func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+            beforeRan = true
+            return nil
+        },
+        AfterUpdate: func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+            afterRan = true
+            return nil
+        },
+    })
+
+    ops := &BulkOps{r: r, ctx: context.Background()}
+    f := map[string]interface{}{"name": "Kenobi"}
+    if err := ops.UpdateOne(f, map[string]interface{}{"name": "Highground"}); err != nil {
+        t.Fatalf("UpdateOne() failed: %v", err)
+    }
+
+    if !beforeRan {
+        t.Error("BeforeUpdate did not run as UpdateOne was queued")
+    }
+    if afterRan {
+        t.Error("AfterUpdate ran before BulkWrite flushed anything")
+    }
+    if len(ops.models) != 1 || len(ops.after) != 1 {
+        t.Fatalf("len(ops.models)=%d len(ops.after)=%d, want 1 and 1", len(ops.models), len(ops.after))
+    }
+}
+
+func TestBulkOps_UpdateOne_EmptyChangesQueuesNothing(t *testing.T) {
+    r := newBulkTestRepository(t)
+
+    ops := &BulkOps{r: r, ctx: context.Background()}
+    if err := ops.UpdateOne(map[string]interface{}{"name": "Kenobi"}, map[string]interface{}{}); err != nil {
+        t.Fatalf("UpdateOne() failed: %v", err)
+    }
+
+    if len(ops.models) != 0 || len(ops.after) != 0 {
+        t.Errorf("len(ops.models)=%d len(ops.after)=%d, want 0 and 0 for an empty update", len(ops.models), len(ops.after))
+    }
+}
+
+func TestBulkOps_DeleteOne_RunsBeforeDeleteAtQueueTime(t *testing.T) {
+    r := newBulkTestRepository(t)
+
+    var beforeRan, afterRan bool
+    r.Use(Hooks{
+        BeforeDelete: func(ctx context.Context, f interfaces.Filter) error {
+            beforeRan = true
+            return nil
+        },
+        AfterDelete: func(ctx context.Context, f interfaces.Filter) error {
+            afterRan = true
+            return nil
+        },
+    })
+
+    ops := &BulkOps{r: r, ctx: context.Background()}
+    if err := ops.DeleteOne(map[string]interface{}{"name": "Kenobi"}); err != nil {
+        t.Fatalf("DeleteOne() failed: %v", err)
+    }
+
+    if !beforeRan {
+        t.Error("BeforeDelete did not run as DeleteOne was queued")
+    }
+    if afterRan {
+        t.Error("AfterDelete ran before BulkWrite flushed anything")
+    }
+    if len(ops.models) != 1 || len(ops.after) != 1 {
+        t.Fatalf("len(ops.models)=%d len(ops.after)=%d, want 1 and 1", len(ops.models), len(ops.after))
+    }
+}
+
+func TestBulkOps_QueuesModelsInCallOrder(t *testing.T) {
+    r := newBulkTestRepository(t)
+
+    ops := &BulkOps{r: r, ctx: context.Background()}
+    if err := ops.InsertOne(&bulkTestDocument{Name: "Kenobi"}); err != nil {
+        t.Fatalf("InsertOne() failed: %v", err)
+    }
+    if err := ops.DeleteOne(map[string]interface{}{"name": "Skywalker"}); err != nil {
+        t.Fatalf("DeleteOne() failed: %v", err)
+    }
+    if err := ops.UpdateOne(map[string]interface{}{"name": "Windu"}, map[string]interface{}{"name": "Fett"}); err != nil {
+        t.Fatalf("UpdateOne() failed: %v", err)
+    }
+
+    if len(ops.models) != 3 {
+        t.Fatalf("len(ops.models) = %d, want 3", len(ops.models))
+    }
+    if len(ops.after) != 3 {
+        t.Fatalf("len(ops.after) = %d, want 3", len(ops.after))
+    }
+
+    if _, ok := ops.models[0].(*mongo.InsertOneModel); !ok {
+        t.Errorf("ops.models[0] = %T, want *mongo.InsertOneModel", ops.models[0])
+    }
+    if _, ok := ops.models[1].(*mongo.DeleteOneModel); !ok {
+        t.Errorf("ops.models[1] = %T, want *mongo.DeleteOneModel", ops.models[1])
+    }
+    if _, ok := ops.models[2].(*mongo.UpdateOneModel); !ok {
+        t.Errorf("ops.models[2] = %T, want *mongo.UpdateOneModel", ops.models[2])
+    }
+}
@@ -2,12 +2,20 @@ package mongodialect
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"net/url"
 	"strings"
 )
 
+// ErrDriverNotConnected occurs when an operation requiring
+// an open connection is attempted before OpenConnection.
+var ErrDriverNotConnected = errors.New("driver has no open connection")
+
 // A Driver is a wrapper for connecting to a MongoDB database.
 type Driver struct {
 	URL      url.URL                // the URL
@@ -62,5 +70,73 @@ func (driver *Driver) IsAlive(ctx context.Context) bool {
 		return false
 	}
 
-	return driver.Client.Ping(ctx, nil) != nil
+	return driver.Client.Ping(ctx, nil) == nil
+}
+
+// HealthCheck pings the database with readpref.Primary(), returning
+// an error if the primary cannot be reached. It is suitable for
+// wiring into a Kubernetes liveness or readiness probe.
+//
+// It fails if the driver has no open connection, or if there is an
+// internal MongoDB error.
+func (driver *Driver) HealthCheck(ctx context.Context) error {
+	if driver.Client == nil {
+		return ErrDriverNotConnected
+	}
+
+	return driver.Client.Ping(ctx, readpref.Primary())
+}
+
+// WithPoolSize sets the minimum and maximum number of connections
+// driver's Client keeps in its connection pool.
+//
+// It must be called before OpenConnection.
+func (driver *Driver) WithPoolSize(min, max uint64) *Driver {
+	driver.Options.SetMinPoolSize(min)
+	driver.Options.SetMaxPoolSize(max)
+	return driver
+}
+
+// WithTLS configures driver's Client to connect using the given TLS config.
+//
+// It must be called before OpenConnection.
+func (driver *Driver) WithTLS(config *tls.Config) *Driver {
+	driver.Options.SetTLSConfig(config)
+	return driver
+}
+
+// WithAuth configures driver's Client to authenticate
+// using the given credentials.
+//
+// It must be called before OpenConnection.
+func (driver *Driver) WithAuth(user, pass, authDB string) *Driver {
+	driver.Options.SetAuth(options.Credential{
+		AuthSource: authDB,
+		Username:   user,
+		Password:   pass,
+	})
+	return driver
+}
+
+// WithReadPreference configures driver's Client to
+// read using the given read preference mode.
+//
+// It must be called before OpenConnection.
+func (driver *Driver) WithReadPreference(mode readpref.Mode) *Driver {
+	pref, err := readpref.New(mode)
+	if err != nil {
+		return driver
+	}
+
+	driver.Options.SetReadPreference(pref)
+	return driver
+}
+
+// WithWriteConcern configures driver's Client to
+// write using the given write concern.
+//
+// It must be called before OpenConnection.
+func (driver *Driver) WithWriteConcern(concern *writeconcern.WriteConcern) *Driver {
+	driver.Options.SetWriteConcern(concern)
+	return driver
 }
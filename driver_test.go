@@ -0,0 +1,81 @@
+package mongodialect
+
+import (
+    "context"
+    "crypto/tls"
+    "go.mongodb.org/mongo-driver/mongo/readpref"
+    "go.mongodb.org/mongo-driver/mongo/writeconcern"
+    "testing"
+)
+
+func newTestDriver() *Driver {
+    return NewDriver(NewDatabaseURL("localhost", 27017), "testBase")
+}
+
+func TestDriver_WithPoolSize(t *testing.T) {
+    driver := newTestDriver().WithPoolSize(5, 10)
+
+    if got := *driver.Options.MinPoolSize; got != 5 {
+        t.Errorf("MinPoolSize = %d, want 5", got)
+    }
+    if got := *driver.Options.MaxPoolSize; got != 10 {
+        t.Errorf("MaxPoolSize = %d, want 10", got)
+    }
+}
+
+func TestDriver_WithTLS(t *testing.T) {
+    config := &tls.Config{ServerName: "example.org"}
+    driver := newTestDriver().WithTLS(config)
+
+    if driver.Options.TLSConfig != config {
+        t.Error("WithTLS did not set driver.Options.TLSConfig")
+    }
+}
+
+func TestDriver_WithAuth(t *testing.T) {
+    driver := newTestDriver().WithAuth("obi-wan", "hunter2", "admin")
+
+    auth := driver.Options.Auth
+    if auth == nil {
+        t.Fatal("WithAuth did not set driver.Options.Auth")
+    }
+    if auth.Username != "obi-wan" || auth.Password != "hunter2" || auth.AuthSource != "admin" {
+        t.Errorf("Auth = %+v, want Username=obi-wan Password=hunter2 AuthSource=admin", auth)
+    }
+}
+
+func TestDriver_WithReadPreference(t *testing.T) {
+    driver := newTestDriver().WithReadPreference(readpref.SecondaryMode)
+
+    if driver.Options.ReadPreference == nil {
+        t.Fatal("WithReadPreference did not set driver.Options.ReadPreference")
+    }
+    if mode := driver.Options.ReadPreference.Mode(); mode != readpref.SecondaryMode {
+        t.Errorf("ReadPreference.Mode() = %v, want %v", mode, readpref.SecondaryMode)
+    }
+}
+
+func TestDriver_WithWriteConcern(t *testing.T) {
+    concern := writeconcern.New(writeconcern.WMajority())
+    driver := newTestDriver().WithWriteConcern(concern)
+
+    if driver.Options.WriteConcern != concern {
+        t.Error("WithWriteConcern did not set driver.Options.WriteConcern")
+    }
+}
+
+func TestDriver_IsAlive_NoClient(t *testing.T) {
+    driver := newTestDriver()
+
+    if driver.IsAlive(context.Background()) {
+        t.Error("IsAlive() = true for a driver with no Client")
+    }
+}
+
+func TestDriver_HealthCheck_NoClient(t *testing.T) {
+    driver := newTestDriver()
+
+    if err := driver.HealthCheck(context.Background()); err != ErrDriverNotConnected {
+        t.Errorf("HealthCheck() = %v, want %v", err, ErrDriverNotConnected)
+    }
+}
@@ -0,0 +1,246 @@
+package generic
+
+import (
+    "context"
+    "errors"
+    "github.com/daspoet/mongodialect"
+    "github.com/daspoet/mongodialect/interfaces"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "reflect"
+    "strings"
+)
+
+var (
+    // ErrDriverNil occurs when a given driver is nil.
+    ErrDriverNil = errors.New("driver must no be nil")
+
+    // ErrCollectionEmpty occurs when the name of a given collection is empty.
+    ErrCollectionEmpty = errors.New("collection must not be empty")
+
+    // ErrDocumentNotFound occurs when a lookup does not yield a result.
+    ErrDocumentNotFound = errors.New("document was not found")
+
+    // ErrMultipleMatches occurs when a lookup
+    // using a given ID yields more than one result.
+    ErrMultipleMatches = errors.New("multiple matches for id")
+)
+
+// A Repository wraps a mongodialect.Driver and provides typed CRUD
+// operations for the documents of type T stored in a single collection.
+//
+// Unlike mongodialect.Repository, Repository decodes query results
+// directly into T using the MongoDB driver's native BSON codec, which
+// removes the reflect.Type bookkeeping and mapstructure round-trip the
+// reflective Repository relies on. The reflective Repository is kept
+// around for callers that cannot express their documents as a concrete
+// Go type at compile time.
+type Repository[T any] struct {
+    idProvider interfaces.IDProvider // generates, parses and locates T's id
+    collection string               // the name of the collection to access
+    Driver     *mongodialect.Driver // the Driver used to connect to the database
+}
+
+// NewRepository returns a new Repository upon validating the given Driver.
+//
+// If idProvider is nil, an interfaces.ObjectIDProvider
+// using Mongo's default id field ("_id") is used instead.
+//
+// It fails if the driver is nil, or if collection is an empty string.
+func NewRepository[T any](driver *mongodialect.Driver, collection string, idProvider interfaces.IDProvider) (*Repository[T], error) {
+    if driver == nil {
+        return nil, ErrDriverNil
+    }
+
+    if collection == "" {
+        return nil, ErrCollectionEmpty
+    }
+
+    if idProvider == nil {
+        idProvider = interfaces.ObjectIDProvider{}
+    }
+
+    return &Repository[T]{
+        idProvider: idProvider,
+        collection: collection,
+        Driver:     driver,
+    }, nil
+}
+
+// Find finds all documents in r's collection matching f and decodes them into T.
+//
+// It fails if the queried data cannot be decoded,
+// or if there is an internal MongoDB error.
+func (r *Repository[T]) Find(ctx context.Context, f interfaces.Filter) ([]T, error) {
+    cursor, err := collection(r).Find(ctx, f)
+    if err != nil {
+        return nil, err
+    }
+
+    var matches []T
+    if err := cursor.All(ctx, &matches); err != nil {
+        return nil, err
+    }
+    return matches, nil
+}
+
+// FindByID finds a document in r's collection that has the given id and decodes it into T.
+//
+// It fails if
+//
+//  1. there is an internal MongoDB error (in which
+//     case the respective error is returned), or
+//
+//  2. if no document is found (in which case
+//     ErrDocumentNotFound is returned), or
+//
+//  3. if multiple documents are found (in which
+//     case ErrMultipleMatches is returned).
+//
+func (r *Repository[T]) FindByID(ctx context.Context, id interface{}) (T, error) {
+    var zero T
+
+    id, err := resolveID(r, id)
+    if err != nil {
+        return zero, err
+    }
+
+    matches, err := r.Find(ctx, map[string]interface{}{
+        r.idProvider.BSONField(): id,
+    })
+
+    if err != nil {
+        return zero, err
+    }
+
+    switch l := len(matches); {
+    case l == 0:
+        return zero, ErrDocumentNotFound
+    case l > 1:
+        return zero, ErrMultipleMatches
+    }
+    return matches[0], nil
+}
+
+// Insert inserts v into r's collection.
+//
+// If v's id field is currently zero, it is
+// populated using r's IDProvider beforehand.
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository[T]) Insert(ctx context.Context, v T) (*mongo.InsertOneResult, error) {
+    populateID(r, &v)
+    return collection(r).InsertOne(ctx, v)
+}
+
+// InsertMany inserts a variadic number of values into r's collection.
+//
+// Zero id fields among v are populated using r's IDProvider beforehand.
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository[T]) InsertMany(ctx context.Context, v ...T) (*mongo.InsertManyResult, error) {
+    docs := make([]interface{}, len(v))
+    for i := range v {
+        populateID(r, &v[i])
+        docs[i] = v[i]
+    }
+    return collection(r).InsertMany(ctx, docs)
+}
+
+// Update updates at most one document in r's collection matching f, using the given changes.
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository[T]) Update(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) (*mongo.UpdateResult, error) {
+    if len(changes) == 0 {
+        return &mongo.UpdateResult{
+            MatchedCount:  0,
+            ModifiedCount: 0,
+            UpsertedCount: 0,
+            UpsertedID:    nil,
+        }, nil
+    }
+    updates := bson.D{{"$set", changes}}
+    return collection(r).UpdateOne(ctx, f, updates)
+}
+
+// UpdateByID updates at most one document in r's collection that has the given id.
+//
+// It falls back to the Repository's Update
+// method, using the provided id as a filter.
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository[T]) UpdateByID(ctx context.Context, id interface{}, changes map[string]interface{}) (*mongo.UpdateResult, error) {
+    id, err := resolveID(r, id)
+    if err != nil {
+        return nil, err
+    }
+
+    return r.Update(ctx, map[string]interface{}{
+        r.idProvider.BSONField(): id,
+    }, changes)
+}
+
+// collection returns a handle for r's collection.
+func collection[T any](r *Repository[T]) *mongo.Collection {
+    db := r.Driver.Client.Database(r.Driver.Database)
+    return db.Collection(r.collection)
+}
+
+// resolveID converts id into the type r's IDProvider expects.
+//
+// If id is a string, it is parsed using r.idProvider.Parse;
+// otherwise it is returned unchanged, since it is assumed
+// to already be of the type the underlying storage expects.
+func resolveID[T any](r *Repository[T], id interface{}) (interface{}, error) {
+    s, ok := id.(string)
+    if !ok {
+        return id, nil
+    }
+    return r.idProvider.Parse(s)
+}
+
+// populateID sets v's id field to a new id generated by
+// r's IDProvider, but only if the field is currently zero.
+//
+// If T has no field mapping to r.idProvider's BSONField,
+// or if that field cannot be set, populateID is a no-op.
+func populateID[T any](r *Repository[T], v *T) {
+    field, ok := fieldByBSONName(reflect.TypeOf(*v), r.idProvider.BSONField())
+    if !ok {
+        return
+    }
+
+    fv := reflect.ValueOf(v).Elem().FieldByIndex(field.Index)
+    if !fv.CanSet() || !fv.IsZero() {
+        return
+    }
+
+    id := reflect.ValueOf(r.idProvider.NewID())
+    if !id.Type().AssignableTo(fv.Type()) {
+        return
+    }
+    fv.Set(id)
+}
+
+// fieldByBSONName returns the field of el mapping to the given
+// BSON field name, honouring a "bson" struct tag where present.
+func fieldByBSONName(el reflect.Type, bsonName string) (reflect.StructField, bool) {
+    if el.Kind() != reflect.Struct {
+        return reflect.StructField{}, false
+    }
+
+    for i := 0; i < el.NumField(); i++ {
+        field := el.Field(i)
+
+        fieldName := field.Name
+        bsonTag, ok := field.Tag.Lookup("bson")
+        if ok {
+            fieldName = strings.Split(strings.Trim(bsonTag, " "), ",")[0]
+        }
+
+        if fieldName == bsonName {
+            return field, true
+        }
+    }
+    return reflect.StructField{}, false
+}
@@ -0,0 +1,148 @@
+package mongodialect
+
+import (
+    "context"
+    "github.com/daspoet/mongodialect/interfaces"
+)
+
+// Hooks defines optional lifecycle callbacks invoked around a
+// Repository's CRUD operations. A nil callback is simply skipped.
+//
+// Hooks are the building block for cross-cutting concerns such as
+// auto-timestamping, soft-delete tombstoning, auto-generated ids, or
+// validation, without requiring callers to wrap every Insert/Update/
+// Delete/Find call themselves.
+type Hooks struct {
+
+    // BeforeInsert is called with the decoded, not-yet-inserted value
+    // before Insert or InsertMany writes it to the collection. If it
+    // returns an error, the insert is aborted and the error returned.
+    BeforeInsert func(ctx context.Context, v interface{}) error
+
+    // AfterInsert is called with the inserted value once Insert or
+    // InsertMany has written it to the collection.
+    AfterInsert func(ctx context.Context, v interface{}) error
+
+    // BeforeUpdate is called with the filter and changes before Update
+    // applies them. If it returns an error, the update is aborted and
+    // the error returned.
+    BeforeUpdate func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error
+
+    // AfterUpdate is called with the filter and changes once Update
+    // has applied them.
+    AfterUpdate func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error
+
+    // BeforeDelete is called with the filter before Delete or
+    // DeleteMany removes the matching document(s). If it returns an
+    // error, the delete is aborted and the error returned.
+    BeforeDelete func(ctx context.Context, f interfaces.Filter) error
+
+    // AfterDelete is called with the filter once Delete or DeleteMany
+    // has removed the matching document(s).
+    AfterDelete func(ctx context.Context, f interfaces.Filter) error
+
+    // AfterFind is called with each decoded value a Find (or
+    // FindByID) call yields. If it returns an error, the call
+    // returns that error instead of any of its matches.
+    AfterFind func(ctx context.Context, v interface{}) error
+}
+
+// Use registers hooks on r.
+//
+// Hooks compose: calling Use more than once stacks the given hooks on
+// top of those already registered, rather than replacing them. Hooks
+// of the same kind run in the order they were registered.
+func (r *Repository) Use(hooks Hooks) {
+    r.hooks = append(r.hooks, hooks)
+}
+
+// beforeInsert runs all of r's registered BeforeInsert hooks for v, in order.
+func beforeInsert(r *Repository, ctx context.Context, v interface{}) error {
+    for _, h := range r.hooks {
+        if h.BeforeInsert == nil {
+            continue
+        }
+        if err := h.BeforeInsert(ctx, v); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// afterInsert runs all of r's registered AfterInsert hooks for v, in order.
+func afterInsert(r *Repository, ctx context.Context, v interface{}) error {
+    for _, h := range r.hooks {
+        if h.AfterInsert == nil {
+            continue
+        }
+        if err := h.AfterInsert(ctx, v); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// beforeUpdate runs all of r's registered BeforeUpdate hooks, in order.
+func beforeUpdate(r *Repository, ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+    for _, h := range r.hooks {
+        if h.BeforeUpdate == nil {
+            continue
+        }
+        if err := h.BeforeUpdate(ctx, f, changes); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// afterUpdate runs all of r's registered AfterUpdate hooks, in order.
+func afterUpdate(r *Repository, ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+    for _, h := range r.hooks {
+        if h.AfterUpdate == nil {
+            continue
+        }
+        if err := h.AfterUpdate(ctx, f, changes); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// beforeDelete runs all of r's registered BeforeDelete hooks, in order.
+func beforeDelete(r *Repository, ctx context.Context, f interfaces.Filter) error {
+    for _, h := range r.hooks {
+        if h.BeforeDelete == nil {
+            continue
+        }
+        if err := h.BeforeDelete(ctx, f); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// afterDelete runs all of r's registered AfterDelete hooks, in order.
+func afterDelete(r *Repository, ctx context.Context, f interfaces.Filter) error {
+    for _, h := range r.hooks {
+        if h.AfterDelete == nil {
+            continue
+        }
+        if err := h.AfterDelete(ctx, f); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// afterFind runs all of r's registered AfterFind hooks for v, in order.
+func afterFind(r *Repository, ctx context.Context, v interface{}) error {
+    for _, h := range r.hooks {
+        if h.AfterFind == nil {
+            continue
+        }
+        if err := h.AfterFind(ctx, v); err != nil {
+            return err
+        }
+    }
+    return nil
+}
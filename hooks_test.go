@@ -0,0 +1,88 @@
+package mongodialect
+
+import (
+    "context"
+    "errors"
+    "github.com/daspoet/mongodialect/interfaces"
+    "testing"
+)
+
+func TestHooks_RunInRegistrationOrder(t *testing.T) {
+    var order []string
+
+    r := &Repository{}
+    r.Use(Hooks{
+        BeforeInsert: func(ctx context.Context, v interface{}) error {
+            order = append(order, "first")
+            return nil
+        },
+    })
+    r.Use(Hooks{
+        BeforeInsert: func(ctx context.Context, v interface{}) error {
+            order = append(order, "second")
+            return nil
+        },
+    })
+
+    if err := beforeInsert(r, context.Background(), nil); err != nil {
+        t.Fatalf("beforeInsert() failed: %v", err)
+    }
+
+    want := []string{"first", "second"}
+    if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+        t.Errorf("hooks ran in order %v, want %v", order, want)
+    }
+}
+
+func TestHooks_NilCallbacksAreSkipped(t *testing.T) {
+    r := &Repository{}
+    r.Use(Hooks{})
+
+    if err := beforeInsert(r, context.Background(), nil); err != nil {
+        t.Errorf("beforeInsert() with a nil BeforeInsert should be a no-op, got %v", err)
+    }
+    if err := afterInsert(r, context.Background(), nil); err != nil {
+        t.Errorf("afterInsert() with a nil AfterInsert should be a no-op, got %v", err)
+    }
+    if err := afterFind(r, context.Background(), nil); err != nil {
+        t.Errorf("afterFind() with a nil AfterFind should be a no-op, got %v", err)
+    }
+}
+
+func TestHooks_StopsAtFirstError(t *testing.T) {
+    errBoom := errors.New("boom")
+    var ran []string
+
+    r := &Repository{}
+    r.Use(Hooks{
+        BeforeUpdate: func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+            ran = append(ran, "first")
+            return errBoom
+        },
+    })
+    r.Use(Hooks{
+        BeforeUpdate: func(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) error {
+            ran = append(ran, "second")
+            return nil
+        },
+    })
+
+    err := beforeUpdate(r, context.Background(), nil, nil)
+    if !errors.Is(err, errBoom) {
+        t.Errorf("beforeUpdate() = %v, want %v", err, errBoom)
+    }
+    if len(ran) != 1 || ran[0] != "first" {
+        t.Errorf("hooks ran = %v, want only the first hook to run", ran)
+    }
+}
+
+func TestUse_StacksRatherThanReplaces(t *testing.T) {
+    r := &Repository{}
+    r.Use(Hooks{})
+    r.Use(Hooks{})
+    r.Use(Hooks{})
+
+    if len(r.hooks) != 3 {
+        t.Errorf("len(r.hooks) = %d, want 3", len(r.hooks))
+    }
+}
@@ -0,0 +1,161 @@
+package mongodialect
+
+import (
+    "context"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// indexTag is the first element of a `mongodialect` struct
+// tag that declares a field as carrying an index.
+const indexTag = "index"
+
+// IndexSpec describes a single index on a Repository's collection.
+type IndexSpec struct {
+    Name   string        // the name of the index
+    Field  string        // the bson field the index is built on
+    Unique bool          // whether the index enforces uniqueness
+    Sparse bool          // whether the index is sparse
+    TTL    time.Duration // if non-zero, documents are dropped TTL after the indexed field's timestamp
+}
+
+// EnsureIndexes reads the `mongodialect:"index,..."` struct tags
+// declared on r's base type and creates the corresponding indexes
+// on r's collection.
+//
+// A field is indexed by tagging it mongodialect:"index", optionally
+// followed by any combination of "unique", "sparse", and "ttl=<seconds>",
+// e.g. mongodialect:"index,unique" or mongodialect:"index,sparse,ttl=3600".
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository) EnsureIndexes(ctx context.Context) error {
+    specs := indexSpecs(r.baseType.Elem())
+    if len(specs) == 0 {
+        return nil
+    }
+
+    models := make([]mongo.IndexModel, len(specs))
+    for i, spec := range specs {
+        opts := options.Index().SetName(spec.Name).SetUnique(spec.Unique).SetSparse(spec.Sparse)
+        if spec.TTL > 0 {
+            opts.SetExpireAfterSeconds(int32(spec.TTL.Seconds()))
+        }
+
+        models[i] = mongo.IndexModel{
+            Keys:    bson.D{{spec.Field, 1}},
+            Options: opts,
+        }
+    }
+
+    _, err := collection(r).Indexes().CreateMany(ctx, models)
+    return err
+}
+
+// DropIndex drops the index with the given name from r's collection.
+//
+// It fails if there is an internal MongoDB error.
+func (r *Repository) DropIndex(ctx context.Context, name string) error {
+    _, err := collection(r).Indexes().DropOne(ctx, name)
+    return err
+}
+
+// Indexes returns the IndexSpecs describing the
+// indexes currently defined on r's collection,
+// except the default "_id_" index.
+//
+// It fails if the index information cannot be decoded,
+// or if there is an internal MongoDB error.
+func (r *Repository) Indexes(ctx context.Context) ([]IndexSpec, error) {
+    cursor, err := collection(r).Indexes().List(ctx)
+    if err != nil {
+        return nil, err
+    }
+
+    var raw []bson.M
+    if err := cursor.All(ctx, &raw); err != nil {
+        return nil, err
+    }
+
+    var specs []IndexSpec
+    for _, doc := range raw {
+        name, _ := doc["name"].(string)
+
+        // every collection has this index by default; it is not
+        // declared via a struct tag, so it is not reported here
+        if name == "_id_" {
+            continue
+        }
+
+        var field string
+        if keys, ok := doc["key"].(bson.M); ok {
+            for k := range keys {
+                field = k
+                break
+            }
+        }
+
+        spec := IndexSpec{Name: name, Field: field}
+        if unique, ok := doc["unique"].(bool); ok {
+            spec.Unique = unique
+        }
+        if sparse, ok := doc["sparse"].(bool); ok {
+            spec.Sparse = sparse
+        }
+        if ttl, ok := doc["expireAfterSeconds"].(int32); ok {
+            spec.TTL = time.Duration(ttl) * time.Second
+        }
+        specs = append(specs, spec)
+    }
+    return specs, nil
+}
+
+// indexSpecs returns the IndexSpecs declared via
+// `mongodialect:"index,..."` struct tags on el.
+func indexSpecs(el reflect.Type) []IndexSpec {
+    var specs []IndexSpec
+
+    for i := 0; i < el.NumField(); i++ {
+        field := el.Field(i)
+
+        tag, ok := field.Tag.Lookup("mongodialect")
+        if !ok {
+            continue
+        }
+
+        opts := strings.Split(tag, ",")
+        if opts[0] != indexTag {
+            continue
+        }
+
+        fieldName := field.Name
+        if bsonTag, ok := field.Tag.Lookup("bson"); ok {
+            fieldName = strings.Split(strings.Trim(bsonTag, " "), ",")[0]
+        }
+
+        spec := IndexSpec{
+            Name:  fieldName + "_index",
+            Field: fieldName,
+        }
+
+        for _, opt := range opts[1:] {
+            opt = strings.TrimSpace(opt)
+            switch {
+            case opt == "unique":
+                spec.Unique = true
+            case opt == "sparse":
+                spec.Sparse = true
+            case strings.HasPrefix(opt, "ttl="):
+                if seconds, err := strconv.Atoi(strings.TrimPrefix(opt, "ttl=")); err == nil {
+                    spec.TTL = time.Duration(seconds) * time.Second
+                }
+            }
+        }
+        specs = append(specs, spec)
+    }
+    return specs
+}
@@ -0,0 +1,50 @@
+package mongodialect
+
+import (
+    "reflect"
+    "testing"
+    "time"
+)
+
+type indexedDocument struct {
+    Name    string `bson:"name"`
+    Email   string `bson:"email" mongodialect:"index,unique"`
+    Deleted bool   `bson:"deleted" mongodialect:"index,sparse,ttl=3600"`
+    Age     int    `bson:"age"`
+}
+
+func TestIndexSpecs(t *testing.T) {
+    specs := indexSpecs(reflect.TypeOf(indexedDocument{}))
+
+    want := []IndexSpec{
+        {Name: "email_index", Field: "email", Unique: true},
+        {Name: "deleted_index", Field: "deleted", Sparse: true, TTL: 3600 * time.Second},
+    }
+
+    if !reflect.DeepEqual(specs, want) {
+        t.Errorf("indexSpecs() = %+v, want %+v", specs, want)
+    }
+}
+
+func TestIndexSpecs_NoTags(t *testing.T) {
+    type plainDocument struct {
+        Name string `bson:"name"`
+    }
+
+    if specs := indexSpecs(reflect.TypeOf(plainDocument{})); specs != nil {
+        t.Errorf("indexSpecs() = %+v, want nil", specs)
+    }
+}
+
+func TestIndexSpecs_InvalidTTLIgnored(t *testing.T) {
+    type badTTLDocument struct {
+        Name string `bson:"name" mongodialect:"index,ttl=not-a-number"`
+    }
+
+    specs := indexSpecs(reflect.TypeOf(badTTLDocument{}))
+    want := []IndexSpec{{Name: "name_index", Field: "name"}}
+
+    if !reflect.DeepEqual(specs, want) {
+        t.Errorf("indexSpecs() = %+v, want %+v", specs, want)
+    }
+}
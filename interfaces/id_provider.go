@@ -0,0 +1,142 @@
+package interfaces
+
+import (
+    "errors"
+    "github.com/google/uuid"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrCompositeIDNotParseable occurs when Parse is
+// called on a CompositeIDProvider.
+var ErrCompositeIDNotParseable = errors.New("composite ids cannot be parsed from a string")
+
+// IDProvider abstracts the generation, parsing, and field mapping of a
+// document's id, allowing a Repository to work with ids of different
+// underlying types (ObjectID, string, UUID, or a composite key) instead
+// of assuming every id is a bare string.
+type IDProvider interface {
+
+    // NewID generates a new, unique id.
+    NewID() interface{}
+
+    // Parse converts the string representation of an id into
+    // the type the underlying storage expects.
+    //
+    // It fails if s cannot be parsed.
+    Parse(s string) (interface{}, error)
+
+    // BSONField returns the name of the BSON field an id is stored under.
+    BSONField() string
+}
+
+// ObjectIDProvider is an IDProvider that generates and
+// parses primitive.ObjectID ids.
+type ObjectIDProvider struct {
+
+    // Field is the name of the BSON field an id is stored under.
+    // If empty, Mongo's default id field ("_id") is used instead.
+    Field string
+}
+
+// NewID generates a new primitive.ObjectID.
+func (p ObjectIDProvider) NewID() interface{} {
+    return primitive.NewObjectID()
+}
+
+// Parse parses s as the hex representation of a primitive.ObjectID.
+func (p ObjectIDProvider) Parse(s string) (interface{}, error) {
+    return primitive.ObjectIDFromHex(s)
+}
+
+// BSONField returns p.Field, falling back to "_id" if p.Field is empty.
+func (p ObjectIDProvider) BSONField() string {
+    if p.Field == "" {
+        return "_id"
+    }
+    return p.Field
+}
+
+// StringIDProvider is an IDProvider that uses
+// opaque, hex-encoded strings as ids.
+type StringIDProvider struct {
+
+    // Field is the name of the BSON field an id is stored under.
+    // If empty, Mongo's default id field ("_id") is used instead.
+    Field string
+}
+
+// NewID generates a new id by hex-encoding a primitive.ObjectID.
+func (p StringIDProvider) NewID() interface{} {
+    return primitive.NewObjectID().Hex()
+}
+
+// Parse returns s as-is, since string ids require no conversion.
+func (p StringIDProvider) Parse(s string) (interface{}, error) {
+    return s, nil
+}
+
+// BSONField returns p.Field, falling back to "_id" if p.Field is empty.
+func (p StringIDProvider) BSONField() string {
+    if p.Field == "" {
+        return "_id"
+    }
+    return p.Field
+}
+
+// UUIDProvider is an IDProvider that generates and parses uuid.UUID ids.
+type UUIDProvider struct {
+
+    // Field is the name of the BSON field an id is stored under.
+    // If empty, Mongo's default id field ("_id") is used instead.
+    Field string
+}
+
+// NewID generates a new random uuid.UUID.
+func (p UUIDProvider) NewID() interface{} {
+    return uuid.New()
+}
+
+// Parse parses s as the string representation of a uuid.UUID.
+func (p UUIDProvider) Parse(s string) (interface{}, error) {
+    return uuid.Parse(s)
+}
+
+// BSONField returns p.Field, falling back to "_id" if p.Field is empty.
+func (p UUIDProvider) BSONField() string {
+    if p.Field == "" {
+        return "_id"
+    }
+    return p.Field
+}
+
+// CompositeIDProvider is an IDProvider for documents whose id is a
+// composite of multiple fields, represented as a bson.D.
+//
+// Composite ids are assembled by callers from a document's own fields
+// rather than generated, so NewID returns an empty bson.D, and Parse
+// always fails since a composite id has no single string representation.
+type CompositeIDProvider struct {
+
+    // Field is the name of the BSON field an id is stored under.
+    // If empty, Mongo's default id field ("_id") is used instead.
+    Field string
+}
+
+// NewID returns an empty bson.D for the caller to populate.
+func (p CompositeIDProvider) NewID() interface{} {
+    return bson.D{}
+}
+
+// Parse always fails, since composite ids cannot be parsed from a string.
+func (p CompositeIDProvider) Parse(s string) (interface{}, error) {
+    return nil, ErrCompositeIDNotParseable
+}
+
+// BSONField returns p.Field, falling back to "_id" if p.Field is empty.
+func (p CompositeIDProvider) BSONField() string {
+    if p.Field == "" {
+        return "_id"
+    }
+    return p.Field
+}
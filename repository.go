@@ -8,6 +8,7 @@ import (
     "github.com/mitchellh/mapstructure"
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
     "reflect"
     "strings"
 )
@@ -25,8 +26,17 @@ var (
     // ErrMultipleMatches occurs when a lookup
     // using a given ID yields more than one result.
     ErrMultipleMatches = errors.New("multiple matches for id")
+
+    // ErrStaleDocument occurs when a protected Update or UpdateByID
+    // matches no document although a document matching its filter
+    // still exists, indicating that it was modified concurrently.
+    ErrStaleDocument = errors.New("document was modified since it was last read")
 )
 
+// protectionTag is the struct tag used to mark
+// a base type's optimistic concurrency key field.
+const protectionTag = "protection"
+
 // A Repository wraps the Driver and provides
 // functionality for performing operations on
 // the collections of a MongoDB database. It
@@ -41,23 +51,25 @@ var (
 // of the data structure contained in the
 // specific collection to access.
 type Repository struct {
-    baseType   reflect.Type // the type of the data structure stored in the collection; must be a pointer
-    idField    string       // the name of the field containing the underlying value's id
-    collection string       // the name of the collection to access
-    Driver     *Driver      // the Driver used to connect to the database
+    baseType        reflect.Type          // the type of the data structure stored in the collection; must be a pointer
+    idProvider      interfaces.IDProvider // generates, parses and locates the underlying value's id
+    protectionField string                // the bson field name of the base type's optimistic concurrency key, or "" if it has none
+    hooks           []Hooks               // lifecycle hooks registered via Use, run in registration order
+    collection      string                // the name of the collection to access
+    Driver          *Driver               // the Driver used to connect to the database
 }
 
 // NewRepository returns a new Repository upon
 // validating the given base type and Driver.
 //
-// If idField is an empty string, the
-// default Mongo id ("_id") is used instead.
+// If idProvider is nil, an interfaces.ObjectIDProvider
+// using Mongo's default id ("_id") is used instead.
 //
 // It fails if the driver is nil, or if
 // the provided base type is not a pointer.
 //
 // It also fails if collection is an empty string.
-func NewRepository(baseType reflect.Type, driver *Driver, collection string, idField string) (*Repository, error) {
+func NewRepository(baseType reflect.Type, driver *Driver, collection string, idProvider interfaces.IDProvider) (*Repository, error) {
     if driver == nil {
         return nil, ErrDriverNil
     }
@@ -71,15 +83,16 @@ func NewRepository(baseType reflect.Type, driver *Driver, collection string, idF
     }
 
     // fallback to Mongo's default id
-    if idField == "" {
-        idField = "_id"
+    if idProvider == nil {
+        idProvider = interfaces.ObjectIDProvider{}
     }
 
     return &Repository{
-        baseType:   baseType,
-        idField:    idField,
-        collection: collection,
-        Driver:     driver,
+        baseType:        baseType,
+        idProvider:      idProvider,
+        protectionField: protectionFieldName(baseType.Elem()),
+        collection:      collection,
+        Driver:          driver,
     }, nil
 }
 
@@ -87,14 +100,14 @@ func NewRepository(baseType reflect.Type, driver *Driver, collection string, idF
 // needed for and combines them into a Repository.
 //
 // see NewRepository
-func InitialiseNewRepository(baseType reflect.Type, port uint, hostname, database, collection, idField string) (*Repository, error) {
+func InitialiseNewRepository(baseType reflect.Type, port uint, hostname, database, collection string, idProvider interfaces.IDProvider) (*Repository, error) {
     url := NewDatabaseURL(hostname, port)
     driver := NewDriver(url, database)
 
     if err := driver.OpenConnection(context.Background()); err != nil {
         return nil, err
     }
-    return NewRepository(baseType, driver, collection, idField)
+    return NewRepository(baseType, driver, collection, idProvider)
 }
 
 // Type returns a pointer to r's base type, which has
@@ -113,7 +126,18 @@ func (r *Repository) Find(ctx context.Context, f interfaces.Filter) ([]interface
     if err != nil {
         return nil, err
     }
-    return decodeCursor(r, cursor)
+
+    matches, err := decodeCursor(r, cursor)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, match := range matches {
+        if err := afterFind(r, ctx, match); err != nil {
+            return nil, err
+        }
+    }
+    return matches, nil
 }
 
 // FindByID finds a document in r's collection that has the
@@ -131,8 +155,13 @@ func (r *Repository) Find(ctx context.Context, f interfaces.Filter) ([]interface
 //     case ErrMultipleMatches is returned).
 //
 func (r *Repository) FindByID(ctx context.Context, id interface{}) (interface{}, error) {
+    id, err := resolveID(r, id)
+    if err != nil {
+        return nil, err
+    }
+
     matches, err := r.Find(ctx, map[string]interface{}{
-        r.idField: id,
+        r.idProvider.BSONField(): id,
     })
 
     if err != nil {
@@ -164,15 +193,22 @@ func (r *Repository) Exists(ctx context.Context, f interfaces.Filter) (bool, err
 // It fails if the queried data cannot be decoded,
 // or if there is an internal MongoDB error.
 func (r *Repository) ExistsByID(ctx context.Context, id interface{}) (bool, error) {
+    id, err := resolveID(r, id)
+    if err != nil {
+        return false, err
+    }
+
     return r.Exists(ctx, map[string]interface{}{
-        r.idField: id,
+        r.idProvider.BSONField(): id,
     })
 }
 
 // Insert inserts a value into r's collection.
 //
-// It decodes v into a value of r's base type,
-// which is subsequently inserted into r's collection.
+// It decodes v into a value of r's base type, and
+// if the resulting value's id field is zero, populates
+// it using r's IDProvider, before inserting it into
+// r's collection.
 //
 // It fails if v cannot be decoded into r's base
 // type, or if there is an internal MongoDB error.
@@ -181,15 +217,29 @@ func (r *Repository) Insert(ctx context.Context, v interface{}) (*mongo.InsertOn
     if err != nil {
         return nil, err
     }
-    return collection(r).InsertOne(ctx, dec)
+    populateID(r, dec)
+
+    if err := beforeInsert(r, ctx, dec); err != nil {
+        return nil, err
+    }
+
+    result, err := collection(r).InsertOne(ctx, dec)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := afterInsert(r, ctx, dec); err != nil {
+        return nil, err
+    }
+    return result, nil
 }
 
 // InsertMany inserts a variadic number
 // of values into r's collection.
 //
-// It decodes each element in v into a value of
-// r's base type, which is subsequently inserted
-// into r's collection.
+// It decodes each element in v into a value of r's
+// base type, populating zero id fields using r's
+// IDProvider, before inserting them into r's collection.
 //
 // It fails if an element of v cannot be decoded into
 // r's base type, or if there is an internal MongoDB error.
@@ -200,9 +250,25 @@ func (r *Repository) InsertMany(ctx context.Context, v ...interface{}) (*mongo.I
         if err != nil {
             return nil, err
         }
+        populateID(r, dec)
+
+        if err := beforeInsert(r, ctx, dec); err != nil {
+            return nil, err
+        }
         decoded[i] = dec
     }
-    return collection(r).InsertMany(ctx, decoded)
+
+    result, err := collection(r).InsertMany(ctx, decoded)
+    if err != nil {
+        return nil, err
+    }
+
+    for _, dec := range decoded {
+        if err := afterInsert(r, ctx, dec); err != nil {
+            return nil, err
+        }
+    }
+    return result, nil
 }
 
 // Update updates at most one document in r's
@@ -212,9 +278,35 @@ func (r *Repository) InsertMany(ctx context.Context, v ...interface{}) (*mongo.I
 // base type, which is subsequently used
 // to update the first document matching f.
 //
+// If r's base type declares a protection field (see protectionTag),
+// Update is always protected: it reads the current value of that
+// field off the document matching f itself (changes need not, and
+// must not, carry it), additionally filters on that value, and
+// atomically increments the field by one, so that concurrent updates
+// to the same document cannot silently overwrite one another. If f
+// no longer matches any document once the protection value is
+// dropped, the update simply matches nothing, as usual; if f still
+// matches a document but the protected update does not, the document
+// was changed concurrently and ErrStaleDocument is returned.
+//
+// If changes is empty, or only carried the protection field, Update
+// is a no-op: this holds even for protected types, so that calling
+// it with nothing to change never spuriously bumps the protection
+// field or risks invalidating a genuine concurrent update.
+//
 // It fails if there is an internal MongoDB error.
 func (r *Repository) Update(ctx context.Context, f interfaces.Filter, changes map[string]interface{}) (*mongo.UpdateResult, error) {
     filterMap(r, changes)
+
+    if err := beforeUpdate(r, ctx, f, changes); err != nil {
+        return nil, err
+    }
+
+    protected := r.protectionField != ""
+    if protected {
+        delete(changes, r.protectionField)
+    }
+
     if len(changes) == 0 {
         return &mongo.UpdateResult{
             MatchedCount:  0,
@@ -223,8 +315,47 @@ func (r *Repository) Update(ctx context.Context, f interfaces.Filter, changes ma
             UpsertedID:    nil,
         }, nil
     }
+
     updates := bson.D{{"$set", changes}}
-    return collection(r).UpdateOne(ctx, f, updates)
+    filter := f
+
+    if protected {
+        current, found, err := currentProtectionValue(r, ctx, f)
+        if err != nil {
+            return nil, err
+        }
+        if !found {
+            return &mongo.UpdateResult{
+                MatchedCount:  0,
+                ModifiedCount: 0,
+                UpsertedCount: 0,
+                UpsertedID:    nil,
+            }, nil
+        }
+
+        filter = withFilterValue(f, r.protectionField, current)
+        updates = append(updates, bson.E{Key: "$inc", Value: bson.M{r.protectionField: 1}})
+    }
+
+    result, err := collection(r).UpdateOne(ctx, filter, updates)
+    if err != nil {
+        return nil, err
+    }
+
+    if protected && result.MatchedCount == 0 {
+        exists, err := r.Exists(ctx, f)
+        if err != nil {
+            return nil, err
+        }
+        if exists {
+            return nil, ErrStaleDocument
+        }
+    }
+
+    if err := afterUpdate(r, ctx, f, changes); err != nil {
+        return nil, err
+    }
+    return result, nil
 }
 
 // UpdateByID updates at most one document
@@ -235,8 +366,13 @@ func (r *Repository) Update(ctx context.Context, f interfaces.Filter, changes ma
 //
 // It fails if there is an internal MongoDB error.
 func (r *Repository) UpdateByID(ctx context.Context, id interface{}, changes map[string]interface{}) (*mongo.UpdateResult, error) {
+    id, err := resolveID(r, id)
+    if err != nil {
+        return nil, err
+    }
+
     return r.Update(ctx, map[string]interface{}{
-        r.idField: id,
+        r.idProvider.BSONField(): id,
     }, changes)
 }
 
@@ -245,7 +381,19 @@ func (r *Repository) UpdateByID(ctx context.Context, id interface{}, changes map
 //
 // It fails if there is an internal MongoDB error.
 func (r *Repository) Delete(ctx context.Context, f interfaces.Filter) (*mongo.DeleteResult, error) {
-    return collection(r).DeleteOne(ctx, f)
+    if err := beforeDelete(r, ctx, f); err != nil {
+        return nil, err
+    }
+
+    result, err := collection(r).DeleteOne(ctx, f)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := afterDelete(r, ctx, f); err != nil {
+        return nil, err
+    }
+    return result, nil
 }
 
 // DeleteMany deletes all documents
@@ -253,7 +401,19 @@ func (r *Repository) Delete(ctx context.Context, f interfaces.Filter) (*mongo.De
 //
 // It fails if there is an internal MongoDB error.
 func (r *Repository) DeleteMany(ctx context.Context, f interfaces.Filter) (*mongo.DeleteResult, error) {
-    return collection(r).DeleteMany(ctx, f)
+    if err := beforeDelete(r, ctx, f); err != nil {
+        return nil, err
+    }
+
+    result, err := collection(r).DeleteMany(ctx, f)
+    if err != nil {
+        return nil, err
+    }
+
+    if err := afterDelete(r, ctx, f); err != nil {
+        return nil, err
+    }
+    return result, nil
 }
 
 // DeleteByID deletes at most one document
@@ -261,8 +421,13 @@ func (r *Repository) DeleteMany(ctx context.Context, f interfaces.Filter) (*mong
 //
 // It fails if there is an internal MongoDB error.
 func (r *Repository) DeleteByID(ctx context.Context, id interface{}) (*mongo.DeleteResult, error) {
+    id, err := resolveID(r, id)
+    if err != nil {
+        return nil, err
+    }
+
     return r.Delete(ctx, map[string]interface{}{
-        r.idField: id,
+        r.idProvider.BSONField(): id,
     })
 }
 
@@ -282,6 +447,112 @@ func decodeIntoBase(r *Repository, v interface{}) (interface{}, error) {
     return dec, err
 }
 
+// resolveID converts id into the type r's IDProvider expects.
+//
+// If id is a string, it is parsed using r.idProvider.Parse;
+// otherwise it is returned unchanged, since it is assumed
+// to already be of the type the underlying storage expects.
+func resolveID(r *Repository, id interface{}) (interface{}, error) {
+    s, ok := id.(string)
+    if !ok {
+        return id, nil
+    }
+    return r.idProvider.Parse(s)
+}
+
+// populateID sets dec's id field to a new id generated by
+// r's IDProvider, but only if the field is currently zero.
+//
+// dec must be a pointer to a value of r's base type. If
+// r's base type has no field mapping to r.idProvider's
+// BSONField, or if that field cannot be set, populateID is a no-op.
+func populateID(r *Repository, dec interface{}) {
+    field, ok := fieldByBSONName(r.baseType.Elem(), r.idProvider.BSONField())
+    if !ok {
+        return
+    }
+
+    v := reflect.ValueOf(dec).Elem().FieldByIndex(field.Index)
+    if !v.CanSet() || !v.IsZero() {
+        return
+    }
+
+    id := reflect.ValueOf(r.idProvider.NewID())
+    if !id.Type().AssignableTo(v.Type()) {
+        return
+    }
+    v.Set(id)
+}
+
+// fieldByBSONName returns the StructField of el whose bson
+// tag (or, absent a tag, field name) matches bsonName.
+func fieldByBSONName(el reflect.Type, bsonName string) (reflect.StructField, bool) {
+    for i := 0; i < el.NumField(); i++ {
+        field := el.Field(i)
+
+        fieldName := field.Name
+        bsonTag, ok := field.Tag.Lookup("bson")
+        if ok {
+            fieldName = strings.Split(strings.Trim(bsonTag, " "), ",")[0]
+        }
+
+        if fieldName == bsonName {
+            return field, true
+        }
+    }
+    return reflect.StructField{}, false
+}
+
+// protectionFieldName returns the bson field name of el's
+// optimistic concurrency key field, as marked by protectionTag,
+// or "" if el declares no such field.
+func protectionFieldName(el reflect.Type) string {
+    for i := 0; i < el.NumField(); i++ {
+        field := el.Field(i)
+
+        if tag, ok := field.Tag.Lookup("mongodialect"); !ok || tag != protectionTag {
+            continue
+        }
+
+        fieldName := field.Name
+        if bsonTag, ok := field.Tag.Lookup("bson"); ok {
+            fieldName = strings.Split(strings.Trim(bsonTag, " "), ",")[0]
+        }
+        return fieldName
+    }
+    return ""
+}
+
+// withFilterValue returns a copy of f with key set to value,
+// leaving f itself untouched.
+func withFilterValue(f interfaces.Filter, key string, value interface{}) interfaces.Filter {
+    merged := make(interfaces.Filter, len(f)+1)
+    for k, v := range f {
+        merged[k] = v
+    }
+    merged[key] = value
+    return merged
+}
+
+// currentProtectionValue reads the current value of r's protection
+// field off the document matching f, projecting only that field so
+// the read neither decodes into r's base type nor runs find hooks.
+//
+// found is false if no document matches f.
+func currentProtectionValue(r *Repository, ctx context.Context, f interfaces.Filter) (value interface{}, found bool, err error) {
+    opts := options.FindOne().SetProjection(bson.M{r.protectionField: 1})
+
+    var doc bson.M
+    err = collection(r).FindOne(ctx, f, opts).Decode(&doc)
+    switch {
+    case err == mongo.ErrNoDocuments:
+        return nil, false, nil
+    case err != nil:
+        return nil, false, err
+    }
+    return doc[r.protectionField], true, nil
+}
+
 // filterMap removes all entries from v that
 // do not refer to fields of r's base type.
 func filterMap(r *Repository, v map[string]interface{}) {
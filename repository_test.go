@@ -0,0 +1,55 @@
+package mongodialect
+
+import (
+    "github.com/daspoet/mongodialect/interfaces"
+    "reflect"
+    "testing"
+)
+
+type protectedDocument struct {
+    Name    string `bson:"name"`
+    Version int    `mongodialect:"protection"`
+}
+
+type renamedProtectedDocument struct {
+    Name    string `bson:"name"`
+    Version int    `bson:"ver, omitempty" mongodialect:"protection"`
+}
+
+type unprotectedDocument struct {
+    Name string `bson:"name"`
+}
+
+func TestProtectionFieldName(t *testing.T) {
+    tests := []struct {
+        name string
+        el   reflect.Type
+        want string
+    }{
+        {"protected", reflect.TypeOf(protectedDocument{}), "Version"},
+        {"protected with bson rename", reflect.TypeOf(renamedProtectedDocument{}), "ver"},
+        {"unprotected", reflect.TypeOf(unprotectedDocument{}), ""},
+    }
+
+    for _, test := range tests {
+        t.Run(test.name, func(t *testing.T) {
+            if got := protectionFieldName(test.el); got != test.want {
+                t.Errorf("protectionFieldName() = %q, want %q", got, test.want)
+            }
+        })
+    }
+}
+
+func TestWithFilterValue(t *testing.T) {
+    f := interfaces.Filter{"name": "Kenobi"}
+
+    merged := withFilterValue(f, "version", 1)
+
+    if _, ok := f["version"]; ok {
+        t.Error("withFilterValue mutated the original filter")
+    }
+
+    if merged["name"] != "Kenobi" || merged["version"] != 1 {
+        t.Errorf("withFilterValue() = %v, want name=Kenobi, version=1", merged)
+    }
+}
@@ -0,0 +1,31 @@
+package mongodialect
+
+import (
+    "context"
+    "go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithSession runs fn inside a MongoDB transaction, started on a new
+// session derived from driver's Client. It uses the session's
+// WithTransaction, which retries both the transaction and its commit
+// on transient errors until they succeed, a non-transient error
+// occurs, or the driver's built-in retry time limit has passed.
+//
+// It fails if driver's Client is nil, or if starting the session, or
+// running or committing the transaction, fails after retries.
+func (driver *Driver) WithSession(ctx context.Context, fn func(sessCtx mongo.SessionContext) error) error {
+    if driver.Client == nil {
+        return ErrDriverNotConnected
+    }
+
+    session, err := driver.Client.StartSession()
+    if err != nil {
+        return err
+    }
+    defer session.EndSession(ctx)
+
+    _, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+        return nil, fn(sessCtx)
+    })
+    return err
+}
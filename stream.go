@@ -0,0 +1,114 @@
+package mongodialect
+
+import (
+    "context"
+    "github.com/daspoet/mongodialect/interfaces"
+    "go.mongodb.org/mongo-driver/mongo/options"
+    "reflect"
+)
+
+// FindOptions configures the behaviour of FindPage.
+type FindOptions struct {
+    Skip  int64       // the number of matching documents to skip
+    Limit int64       // the maximum number of documents to return; 0 means no limit
+    Sort  interface{} // the sort order applied to the matching documents
+}
+
+// FindStream finds all documents in r's collection matching f and streams
+// each decoded value of r's base type on the returned channel as soon as
+// it becomes available, instead of buffering the entire result set in
+// memory like Find does. r's AfterFind hooks, if any, run on each value
+// before it is sent, just as they do for Find.
+//
+// The returned error channel receives at most one error, after which
+// both channels are closed. If no error occurs, the error channel is
+// closed once the result channel is closed.
+//
+// FindStream owns a child of ctx internally and hands back its
+// CancelFunc. Callers that stop draining the result channel before it
+// is closed (e.g. breaking out of a range loop early) MUST call the
+// returned CancelFunc, or the streaming goroutine and its underlying
+// cursor leak forever blocked on sending the next result. It is safe
+// to call the CancelFunc again after the channels are already closed.
+func (r *Repository) FindStream(ctx context.Context, f interfaces.Filter) (<-chan interface{}, <-chan error, context.CancelFunc) {
+    ctx, cancel := context.WithCancel(ctx)
+
+    results := make(chan interface{})
+    errs := make(chan error, 1)
+
+    cursor, err := collection(r).Find(ctx, f)
+    if err != nil {
+        errs <- err
+        close(results)
+        close(errs)
+        return results, errs, cancel
+    }
+
+    el := r.baseType.Elem()
+
+    go func() {
+        defer close(errs)
+        defer close(results)
+        defer cursor.Close(context.Background())
+
+        for cursor.Next(ctx) {
+            v := reflect.New(el).Interface()
+            if err := cursor.Decode(v); err != nil {
+                errs <- err
+                return
+            }
+            if err := afterFind(r, ctx, v); err != nil {
+                errs <- err
+                return
+            }
+
+            select {
+            case results <- v:
+            case <-ctx.Done():
+                return
+            }
+        }
+
+        if err := cursor.Err(); err != nil && ctx.Err() == nil {
+            errs <- err
+        }
+    }()
+
+    return results, errs, cancel
+}
+
+// FindPage finds at most opts.Limit documents in r's collection matching
+// f, skipping the first opts.Skip matches and applying opts.Sort, and
+// additionally returns the total number of documents matching f
+// irrespective of opts.Skip and opts.Limit.
+//
+// It fails if the queried data cannot be decoded,
+// or if there is an internal MongoDB error.
+func (r *Repository) FindPage(ctx context.Context, f interfaces.Filter, opts FindOptions) ([]interface{}, int64, error) {
+    total, err := collection(r).CountDocuments(ctx, f)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    findOpts := options.Find().SetSkip(opts.Skip).SetLimit(opts.Limit)
+    if opts.Sort != nil {
+        findOpts.SetSort(opts.Sort)
+    }
+
+    cursor, err := collection(r).Find(ctx, f, findOpts)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    matches, err := decodeCursor(r, cursor)
+    if err != nil {
+        return nil, 0, err
+    }
+
+    for _, match := range matches {
+        if err := afterFind(r, ctx, match); err != nil {
+            return nil, 0, err
+        }
+    }
+    return matches, total, nil
+}
@@ -0,0 +1,54 @@
+package mongodialect
+
+import (
+    "context"
+    "reflect"
+    "testing"
+    "time"
+)
+
+type streamTestDocument struct {
+    Name string `bson:"name"`
+}
+
+// TestFindStream_ErrorClosesChannelsAndCancelIsSafe exercises FindStream's
+// error path against an address nothing listens on, so it never touches a
+// live MongoDB: Find fails fast, both channels close, and the returned
+// CancelFunc remains safe to call even though the goroutine never started.
+func TestFindStream_ErrorClosesChannelsAndCancelIsSafe(t *testing.T) {
+    uri := NewDatabaseURL("127.0.0.1", 1)
+    driver := NewDriver(uri, "testBase")
+    driver.Options.SetServerSelectionTimeout(100 * time.Millisecond)
+
+    if err := driver.OpenConnection(context.Background()); err != nil {
+        t.Fatalf("OpenConnection() failed: %v", err)
+    }
+
+    r, err := NewRepository(reflect.TypeOf(new(streamTestDocument)), driver, "stream", nil)
+    if err != nil {
+        t.Fatalf("NewRepository() failed: %v", err)
+    }
+
+    results, errs, cancel := r.FindStream(context.Background(), map[string]interface{}{})
+
+    select {
+    case _, ok := <-errs:
+        if !ok {
+            t.Error("errs closed without ever sending an error")
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("timed out waiting for Find to fail")
+    }
+
+    if _, ok := <-results; ok {
+        t.Error("results was not closed after Find failed")
+    }
+    if _, ok := <-errs; ok {
+        t.Error("errs was not closed after sending its one error")
+    }
+
+    // Calling cancel after the channels are already closed, and more
+    // than once, must not panic.
+    cancel()
+    cancel()
+}
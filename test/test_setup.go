@@ -44,7 +44,8 @@ func makeRepository() interfaces.Repository {
 	}
 
 	base := reflect.TypeOf(new(testDocument))
-	repository, err := mongodialect.NewRepository(base, driver, "test", "ID")
+	idProvider := interfaces.UUIDProvider{Field: "ID"}
+	repository, err := mongodialect.NewRepository(base, driver, "test", idProvider)
 
 	if err != nil {
 		panic(err)